@@ -0,0 +1,149 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// FunctionRegistry is a mutable collection of named cty functions that
+// can be made available to HCL2 interpolation via an hcl2.EvalContext's
+// Functions map.
+//
+// The zero value of FunctionRegistry is not valid to use; call
+// NewFunctionRegistry to obtain one. A FunctionRegistry is safe for
+// concurrent use.
+type FunctionRegistry struct {
+	mu    sync.RWMutex
+	funcs map[string]function.Function
+}
+
+// NewFunctionRegistry returns a FunctionRegistry pre-populated with the
+// HIL-shimmed functions returned by hcl2InterpolationFuncs, so that
+// callers get Terraform's built-in interpolation functions "for free"
+// and only need to Register the functions they want to add or override.
+func NewFunctionRegistry() *FunctionRegistry {
+	r := &FunctionRegistry{
+		funcs: make(map[string]function.Function),
+	}
+	for name, fn := range hcl2InterpolationFuncs() {
+		// The HIL shims are trusted to have valid signatures, so they're
+		// seeded directly rather than through Register.
+		r.funcs[name] = fn
+	}
+	return r
+}
+
+// Register adds fn to the registry under name, replacing any existing
+// function of the same name. This is how a native cty function (for
+// example, one contributed by a provisioner) shadows the built-in
+// HIL-shimmed function of the same name.
+//
+// Register rejects fn with an error if its signature is incompatible
+// with how the registry's functions get called: a parameter with no
+// type, or a parameter name that collides with another parameter of the
+// same function.
+func (r *FunctionRegistry) Register(name string, fn function.Function) error {
+	if err := validateFunctionSignature(name, fn); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.funcs[name] = fn
+	return nil
+}
+
+// validateFunctionSignature checks fn's parameters for the kinds of
+// mistakes that would otherwise surface confusingly far from the
+// Register call that introduced them: an untyped parameter, or two
+// parameters (fixed or variadic) sharing a name.
+func validateFunctionSignature(name string, fn function.Function) error {
+	seen := make(map[string]bool)
+
+	for _, p := range fn.Params() {
+		if p.Type == cty.NilType {
+			return fmt.Errorf("function %q has an incompatible signature: parameter %q has no type", name, p.Name)
+		}
+		if p.Name != "" {
+			if seen[p.Name] {
+				return fmt.Errorf("function %q has an incompatible signature: duplicate parameter name %q", name, p.Name)
+			}
+			seen[p.Name] = true
+		}
+	}
+
+	if vp := fn.VarParam(); vp != nil {
+		if vp.Type == cty.NilType {
+			return fmt.Errorf("function %q has an incompatible signature: variadic parameter %q has no type", name, vp.Name)
+		}
+		if vp.Name != "" && seen[vp.Name] {
+			return fmt.Errorf("function %q has an incompatible signature: variadic parameter name %q collides with a fixed parameter", name, vp.Name)
+		}
+	}
+
+	return nil
+}
+
+// Lookup returns the function registered under name, and whether one was
+// found.
+func (r *FunctionRegistry) Lookup(name string) (function.Function, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn, ok := r.funcs[name]
+	return fn, ok
+}
+
+// All returns a snapshot of every function currently in the registry,
+// keyed by name, suitable for assigning directly to an hcl2.EvalContext's
+// Functions field.
+func (r *FunctionRegistry) All() map[string]function.Function {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ret := make(map[string]function.Function, len(r.funcs))
+	for name, fn := range r.funcs {
+		ret[name] = fn
+	}
+	return ret
+}
+
+// Names returns the sorted names of every function currently in the
+// registry, mainly for use in error messages and diagnostics.
+func (r *FunctionRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.funcs))
+	for name := range r.funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegisterVariadic is a builder helper for the common case of a function
+// that takes a fixed set of params followed by any number of repetitions
+// of varParam, all sharing a single implementation. It saves callers from
+// repeating the function.Spec/function.New boilerplate for this shape.
+func (r *FunctionRegistry) RegisterVariadic(name string, params []function.Parameter, varParam function.Parameter, returnType function.TypeFunc, impl function.ImplFunc) error {
+	return r.Register(name, function.New(&function.Spec{
+		Params:   params,
+		VarParam: &varParam,
+		Type:     returnType,
+		Impl:     impl,
+	}))
+}
+
+// RegisterDynamic is a builder helper for a function whose return type
+// depends on its arguments (for example, one that returns the same type
+// it was given). returnType is called with the converted argument values
+// to compute the result type for a given call.
+func (r *FunctionRegistry) RegisterDynamic(name string, params []function.Parameter, returnType function.TypeFunc, impl function.ImplFunc) error {
+	return r.Register(name, function.New(&function.Spec{
+		Params: params,
+		Type:   returnType,
+		Impl:   impl,
+	}))
+}
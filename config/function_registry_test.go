@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+func TestFunctionRegistryOverridePrecedence(t *testing.T) {
+	reg := NewFunctionRegistry()
+
+	if _, ok := reg.Lookup("upper"); !ok {
+		t.Fatalf("expected the seeded registry to have the HIL-shimmed \"upper\" function")
+	}
+
+	native := function.New(&function.Spec{
+		Params: []function.Parameter{{Name: "s", Type: cty.String}},
+		Type:   function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return cty.StringVal("native:" + args[0].AsString()), nil
+		},
+	})
+	if err := reg.Register("upper", native); err != nil {
+		t.Fatalf("unexpected error registering override: %s", err)
+	}
+
+	got, ok := reg.Lookup("upper")
+	if !ok {
+		t.Fatalf("expected \"upper\" to still be registered after override")
+	}
+
+	result, err := got.Call([]cty.Value{cty.StringVal("hello")})
+	if err != nil {
+		t.Fatalf("unexpected error calling overridden function: %s", err)
+	}
+	if want := cty.StringVal("native:hello"); !result.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", result, want)
+	}
+
+	all := reg.All()
+	if _, ok := all["upper"]; !ok {
+		t.Fatalf("expected All() to include the overridden \"upper\" function")
+	}
+}
+
+func TestFunctionRegistryIncompatibleSignature(t *testing.T) {
+	reg := NewFunctionRegistry()
+
+	// Two parameters sharing the name "s" is rejected at registration
+	// time, before the function ever gets a chance to be called.
+	dupNames := function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "s", Type: cty.String},
+			{Name: "s", Type: cty.Number},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return args[0], nil
+		},
+	})
+	if err := reg.Register("dupNames", dupNames); err == nil {
+		t.Fatalf("expected an error registering a function with duplicate parameter names")
+	}
+	if _, ok := reg.Lookup("dupNames"); ok {
+		t.Errorf("a function that failed signature validation must not end up in the registry")
+	}
+
+	// A parameter with no type is likewise rejected at registration time.
+	untyped := function.New(&function.Spec{
+		Params: []function.Parameter{{Name: "v"}},
+		Type:   function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return args[0], nil
+		},
+	})
+	if err := reg.Register("untyped", untyped); err == nil {
+		t.Fatalf("expected an error registering a function with an untyped parameter")
+	}
+}
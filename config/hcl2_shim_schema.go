@@ -0,0 +1,337 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/config/configschema"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// ConfigValueFromHCL2WithSchema is a variant of configValueFromHCL2 that
+// takes the configschema.Block that the value is conforming to in addition
+// to the value itself, and walks the value by the schema's attributes and
+// nested block types. The result does not keep list-vs-set or
+// map-vs-object distinctions: lists, sets and tuples all become
+// []interface{}, maps and objects become map[string]interface{}, numbers
+// are converted as configValueFromHCL2 converts them, and nulls become nil.
+//
+// The given value must conform to the implied type of the given schema,
+// or else this function may produce incorrect results or panic.
+func ConfigValueFromHCL2WithSchema(v cty.Value, schema *configschema.Block) interface{} {
+	if schema == nil {
+		return configValueFromHCL2(v)
+	}
+	if v.IsNull() {
+		return nil
+	}
+	if !v.IsKnown() {
+		return UnknownVariableValue
+	}
+
+	return configValueFromHCL2Block(v, schema)
+}
+
+func configValueFromHCL2Block(v cty.Value, schema *configschema.Block) map[string]interface{} {
+	ret := make(map[string]interface{})
+
+	for name, attrS := range schema.Attributes {
+		av := v.GetAttr(name)
+		ret[name] = configValueFromHCL2Typed(av, attrS.Type)
+	}
+
+	for name, blockS := range schema.BlockTypes {
+		bv := v.GetAttr(name)
+		ret[name] = configValueFromHCL2NestedBlock(bv, blockS)
+	}
+
+	return ret
+}
+
+func configValueFromHCL2NestedBlock(v cty.Value, blockS *configschema.NestedBlock) interface{} {
+	if v.IsNull() {
+		return nil
+	}
+	if !v.IsKnown() {
+		return UnknownVariableValue
+	}
+
+	switch blockS.Nesting {
+	case configschema.NestingSingle:
+		return configValueFromHCL2Block(v, &blockS.Block)
+	case configschema.NestingList, configschema.NestingSet:
+		var ret []interface{}
+		for it := v.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			ret = append(ret, configValueFromHCL2Block(ev, &blockS.Block))
+		}
+		return ret
+	case configschema.NestingMap:
+		ret := make(map[string]interface{})
+		for it := v.ElementIterator(); it.Next(); {
+			kv, ev := it.Element()
+			ret[kv.AsString()] = configValueFromHCL2Block(ev, &blockS.Block)
+		}
+		return ret
+	default:
+		panic(fmt.Sprintf("unsupported nesting mode %#v", blockS.Nesting))
+	}
+}
+
+// configValueFromHCL2Typed is like configValueFromHCL2 but consults the
+// given cty.Type to decide how to walk collections: lists, sets and tuples
+// all become []interface{} (so the distinction between them is not kept),
+// and maps and objects become map[string]interface{}. A null of any type
+// becomes an untyped nil.
+func configValueFromHCL2Typed(v cty.Value, ty cty.Type) interface{} {
+	if v.IsNull() {
+		return nil
+	}
+	if !v.IsKnown() {
+		return UnknownVariableValue
+	}
+
+	switch {
+	case ty.IsListType(), ty.IsSetType(), ty.IsTupleType():
+		var ret []interface{}
+		for it := v.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			ret = append(ret, configValueFromHCL2Typed(ev, ev.Type()))
+		}
+		return ret
+	case ty.IsMapType(), ty.IsObjectType():
+		ret := make(map[string]interface{})
+		for it := v.ElementIterator(); it.Next(); {
+			kv, ev := it.Element()
+			ret[kv.AsString()] = configValueFromHCL2Typed(ev, ev.Type())
+		}
+		return ret
+	default:
+		return configValueFromHCL2(v)
+	}
+}
+
+// HCL2ValueFromConfigValueWithSchema is a variant of hcl2ValueFromConfigValue
+// that takes a configschema.Block describing the expected shape of the
+// result, and uses it to produce a value of the schema's implied type
+// rather than guessing a type from the shape of raw.
+//
+// This allows a provider to distinguish between an empty collection and a
+// null one, and preserves list-vs-set-vs-map distinctions that would
+// otherwise be lost when lowering to the legacy map[string]interface{}
+// representation.
+func HCL2ValueFromConfigValueWithSchema(raw interface{}, schema *configschema.Block) (cty.Value, error) {
+	if schema == nil {
+		return hcl2ValueFromConfigValue(raw), nil
+	}
+	if raw == nil {
+		return cty.NullVal(schema.ImpliedType()), nil
+	}
+
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return cty.DynamicVal, fmt.Errorf("root config value must be a map, got %T", raw)
+	}
+
+	return hcl2ValueFromConfigValueBlock(m, schema)
+}
+
+func hcl2ValueFromConfigValueBlock(m map[string]interface{}, schema *configschema.Block) (cty.Value, error) {
+	vals := make(map[string]cty.Value)
+
+	for name, attrS := range schema.Attributes {
+		v, err := hcl2ValueFromConfigValueTyped(m[name], attrS.Type)
+		if err != nil {
+			return cty.DynamicVal, fmt.Errorf("attribute %q: %s", name, err)
+		}
+		vals[name] = v
+	}
+
+	for name, blockS := range schema.BlockTypes {
+		v, err := hcl2ValueFromConfigValueNestedBlock(m[name], blockS)
+		if err != nil {
+			return cty.DynamicVal, fmt.Errorf("block %q: %s", name, err)
+		}
+		vals[name] = v
+	}
+
+	return cty.ObjectVal(vals), nil
+}
+
+func hcl2ValueFromConfigValueNestedBlock(raw interface{}, blockS *configschema.NestedBlock) (cty.Value, error) {
+	switch blockS.Nesting {
+	case configschema.NestingSingle:
+		if raw == nil {
+			return cty.NullVal(blockS.Block.ImpliedType()), nil
+		}
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return cty.DynamicVal, fmt.Errorf("must be a single block, got %T", raw)
+		}
+		return hcl2ValueFromConfigValueBlock(m, &blockS.Block)
+
+	case configschema.NestingList, configschema.NestingSet:
+		emptyVal := cty.ListValEmpty(blockS.Block.ImpliedType())
+		if blockS.Nesting == configschema.NestingSet {
+			emptyVal = cty.SetValEmpty(blockS.Block.ImpliedType())
+		}
+		if raw == nil {
+			return emptyVal, nil
+		}
+		s, ok := raw.([]interface{})
+		if !ok {
+			return cty.DynamicVal, fmt.Errorf("must be a list of blocks, got %T", raw)
+		}
+		if len(s) == 0 {
+			return emptyVal, nil
+		}
+		elems := make([]cty.Value, len(s))
+		for i, rawElem := range s {
+			em, ok := rawElem.(map[string]interface{})
+			if !ok {
+				return cty.DynamicVal, fmt.Errorf("element %d: must be a block, got %T", i, rawElem)
+			}
+			ev, err := hcl2ValueFromConfigValueBlock(em, &blockS.Block)
+			if err != nil {
+				return cty.DynamicVal, fmt.Errorf("element %d: %s", i, err)
+			}
+			elems[i] = ev
+		}
+		if blockS.Nesting == configschema.NestingSet {
+			return cty.SetVal(elems), nil
+		}
+		return cty.ListVal(elems), nil
+
+	case configschema.NestingMap:
+		if raw == nil {
+			return cty.MapValEmpty(blockS.Block.ImpliedType()), nil
+		}
+		s, ok := raw.(map[string]interface{})
+		if !ok {
+			return cty.DynamicVal, fmt.Errorf("must be a map of blocks, got %T", raw)
+		}
+		if len(s) == 0 {
+			return cty.MapValEmpty(blockS.Block.ImpliedType()), nil
+		}
+		elems := make(map[string]cty.Value, len(s))
+		for k, rawElem := range s {
+			em, ok := rawElem.(map[string]interface{})
+			if !ok {
+				return cty.DynamicVal, fmt.Errorf("element %q: must be a block, got %T", k, rawElem)
+			}
+			ev, err := hcl2ValueFromConfigValueBlock(em, &blockS.Block)
+			if err != nil {
+				return cty.DynamicVal, fmt.Errorf("element %q: %s", k, err)
+			}
+			elems[k] = ev
+		}
+		return cty.MapVal(elems), nil
+
+	default:
+		return cty.DynamicVal, fmt.Errorf("unsupported nesting mode %#v", blockS.Nesting)
+	}
+}
+
+// hcl2ValueFromConfigValueTyped is like hcl2ValueFromConfigValue but
+// coerces the result into the given cty.Type rather than inferring a type
+// from the shape of raw. This lets it tell an empty list from an empty
+// set from an empty tuple, none of which raw can distinguish on its own,
+// and it produces a properly-typed null instead of cty.NullVal(DynamicPseudoType)
+// when raw is nil.
+func hcl2ValueFromConfigValueTyped(raw interface{}, ty cty.Type) (cty.Value, error) {
+	if raw == nil {
+		return cty.NullVal(ty), nil
+	}
+	if raw == UnknownVariableValue {
+		return cty.UnknownVal(ty), nil
+	}
+
+	switch {
+	case ty.IsListType(), ty.IsSetType():
+		s, ok := raw.([]interface{})
+		if !ok {
+			return cty.DynamicVal, fmt.Errorf("must be a list, got %T", raw)
+		}
+		ety := ty.ElementType()
+		if len(s) == 0 {
+			if ty.IsSetType() {
+				return cty.SetValEmpty(ety), nil
+			}
+			return cty.ListValEmpty(ety), nil
+		}
+		elems := make([]cty.Value, len(s))
+		for i, re := range s {
+			ev, err := hcl2ValueFromConfigValueTyped(re, ety)
+			if err != nil {
+				return cty.DynamicVal, fmt.Errorf("element %d: %s", i, err)
+			}
+			elems[i] = ev
+		}
+		if ty.IsSetType() {
+			return cty.SetVal(elems), nil
+		}
+		return cty.ListVal(elems), nil
+
+	case ty.IsMapType():
+		s, ok := raw.(map[string]interface{})
+		if !ok {
+			return cty.DynamicVal, fmt.Errorf("must be a map, got %T", raw)
+		}
+		ety := ty.ElementType()
+		if len(s) == 0 {
+			return cty.MapValEmpty(ety), nil
+		}
+		elems := make(map[string]cty.Value, len(s))
+		for k, re := range s {
+			ev, err := hcl2ValueFromConfigValueTyped(re, ety)
+			if err != nil {
+				return cty.DynamicVal, fmt.Errorf("element %q: %s", k, err)
+			}
+			elems[k] = ev
+		}
+		return cty.MapVal(elems), nil
+
+	case ty.IsObjectType():
+		s, ok := raw.(map[string]interface{})
+		if !ok {
+			return cty.DynamicVal, fmt.Errorf("must be a map, got %T", raw)
+		}
+		atys := ty.AttributeTypes()
+		elems := make(map[string]cty.Value, len(atys))
+		for name, aty := range atys {
+			ev, err := hcl2ValueFromConfigValueTyped(s[name], aty)
+			if err != nil {
+				return cty.DynamicVal, fmt.Errorf("attribute %q: %s", name, err)
+			}
+			elems[name] = ev
+		}
+		return cty.ObjectVal(elems), nil
+
+	case ty == cty.Number:
+		switch tv := raw.(type) {
+		case int:
+			return cty.NumberIntVal(int64(tv)), nil
+		case float64:
+			return cty.NumberFloatVal(tv), nil
+		default:
+			// Legacy interpolated config routinely represents numbers as
+			// strings (e.g. the result of a string-returning interpolation
+			// that happens to look numeric), so fall through to the
+			// generic string-aware conversion rather than rejecting it.
+			v := hcl2ValueFromConfigValue(raw)
+			conv, err := convert.Convert(v, ty)
+			if err != nil {
+				return cty.DynamicVal, fmt.Errorf("must be a number, got %T", raw)
+			}
+			return conv, nil
+		}
+
+	default:
+		v := hcl2ValueFromConfigValue(raw)
+		conv, err := convert.Convert(v, ty)
+		if err != nil {
+			return cty.DynamicVal, err
+		}
+		return conv, nil
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/config/configschema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestHCL2ValueFromConfigValueWithSchemaSetBlocks(t *testing.T) {
+	schema := &configschema.Block{
+		BlockTypes: map[string]*configschema.NestedBlock{
+			"rule": {
+				Nesting: configschema.NestingSet,
+				Block: configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"name": {Type: cty.String, Required: true},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		Name string
+		Raw  interface{}
+	}{
+		{"null", map[string]interface{}{"rule": nil}},
+		{"empty", map[string]interface{}{"rule": []interface{}{}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got, err := HCL2ValueFromConfigValueWithSchema(test.Raw, schema)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			rule := got.GetAttr("rule")
+			if !rule.Type().IsSetType() {
+				t.Fatalf("wrong type for %q\ngot:  %#v\nwant: a set type", test.Name, rule.Type())
+			}
+		})
+	}
+}
+
+func TestHCL2ValueFromConfigValueWithSchemaNumericString(t *testing.T) {
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"count": {Type: cty.Number, Optional: true},
+		},
+	}
+
+	got, err := HCL2ValueFromConfigValueWithSchema(map[string]interface{}{
+		"count": "3",
+	}, schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := cty.NumberIntVal(3)
+	if gotCount := got.GetAttr("count"); !gotCount.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", gotCount, want)
+	}
+}
@@ -0,0 +1,36 @@
+package config
+
+import "github.com/hashicorp/terraform/config/configschema"
+
+// UnknownVariableValue is a sentinel value used to denote that a value
+// couldn't be determined at the time an interpolation ran because it
+// depends on something (e.g. a computed resource attribute) that won't
+// be known until apply time.
+const UnknownVariableValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+// RawConfig holds the raw, uninterpolated configuration for a resource or
+// provider block as parsed out of a module, along with the schema-aware
+// bits EvalInterpolate and ProviderEvalTree need in order to interpolate
+// and (optionally) reshape it.
+type RawConfig struct {
+	// Raw is the raw key/value pairs parsed from configuration, before
+	// interpolation.
+	Raw map[string]interface{}
+
+	// Schema, if non-nil, describes the shape Raw is expected to conform
+	// to. RequiresSchema reports whether it's set.
+	Schema *configschema.Block
+}
+
+// NewRawConfig returns a RawConfig wrapping raw, with no schema attached.
+func NewRawConfig(raw map[string]interface{}) *RawConfig {
+	return &RawConfig{Raw: raw}
+}
+
+// RequiresSchema reports whether this configuration has a schema attached
+// that its interpolated result should be reshaped against. ProviderEvalTree
+// uses this to decide whether it's worth fetching the provider's schema
+// before interpolating.
+func (c *RawConfig) RequiresSchema() bool {
+	return c.Schema != nil
+}
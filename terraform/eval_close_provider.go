@@ -0,0 +1,18 @@
+package terraform
+
+// EvalCloseProvider is an EvalNode implementation that closes provider
+// connections that aren't needed anymore.
+type EvalCloseProvider struct {
+	Name string
+}
+
+func (n *EvalCloseProvider) Eval(ctx EvalContext) (interface{}, error) {
+	ctx.CloseProvider(n.Name)
+
+	// The plugin process behind this provider is going away, so any
+	// schema we cached for it may no longer be valid for whatever
+	// version of the provider (if any) replaces it.
+	ctx.SchemaCache().Remove(n.Name)
+
+	return nil, nil
+}
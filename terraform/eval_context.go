@@ -0,0 +1,37 @@
+package terraform
+
+import (
+	"github.com/hashicorp/terraform/config"
+)
+
+// EvalContext is the interface that is given to each EvalNode's Eval
+// method and provides access to the state shared across a single graph
+// walk: initialized providers, the process-wide schema cache, and
+// configuration interpolation.
+//
+// This is the slice of EvalContext that the provider-evaluation nodes in
+// this package depend on; the full interface used by the rest of the
+// graph has many more methods for state, diffs, hooks, and so on.
+type EvalContext interface {
+	// Provider returns the already-initialized provider with the given
+	// name, or nil if EvalInitProvider hasn't run for it yet.
+	Provider(n string) ResourceProvider
+
+	// CloseProvider closes the connection to the given provider and
+	// removes it from the set of initialized providers.
+	CloseProvider(n string) error
+
+	// SchemaCache returns the process-wide cache of provider schemas
+	// shared by every node across a walk.
+	SchemaCache() *SchemaCache
+
+	// Interpolate interpolates the given raw configuration into a
+	// ResourceConfig.
+	Interpolate(*config.RawConfig, *Resource) (*ResourceConfig, error)
+
+	// Functions returns the FunctionRegistry in scope for this context.
+	// It is nil if the context (and so the module it belongs to) hasn't
+	// been given project-specific functions to work with.
+	// BuiltinEvalContext.Interpolate does not consult it.
+	Functions() *config.FunctionRegistry
+}
@@ -0,0 +1,55 @@
+package terraform
+
+import (
+	"sync"
+
+	"github.com/hashicorp/terraform/config"
+)
+
+// BuiltinEvalContext is the main, non-test implementation of EvalContext,
+// shared by every EvalNode in a single graph walk.
+type BuiltinEvalContext struct {
+	ProvidersLock sync.Mutex
+	ProvidersMap  map[string]ResourceProvider
+
+	SchemaCacheValue *SchemaCache
+
+	FunctionsValue *config.FunctionRegistry
+}
+
+func (ctx *BuiltinEvalContext) Provider(n string) ResourceProvider {
+	ctx.ProvidersLock.Lock()
+	defer ctx.ProvidersLock.Unlock()
+	return ctx.ProvidersMap[n]
+}
+
+func (ctx *BuiltinEvalContext) CloseProvider(n string) error {
+	ctx.ProvidersLock.Lock()
+	defer ctx.ProvidersLock.Unlock()
+	delete(ctx.ProvidersMap, n)
+	return nil
+}
+
+func (ctx *BuiltinEvalContext) SchemaCache() *SchemaCache {
+	return ctx.SchemaCacheValue
+}
+
+func (ctx *BuiltinEvalContext) Functions() *config.FunctionRegistry {
+	return ctx.FunctionsValue
+}
+
+// Interpolate copies the given raw configuration's values, as they are,
+// into a new ResourceConfig. It evaluates no expressions and does not
+// consult Functions(); a nil rc yields an empty ResourceConfig.
+func (ctx *BuiltinEvalContext) Interpolate(rc *config.RawConfig, r *Resource) (*ResourceConfig, error) {
+	if rc == nil {
+		return NewResourceConfig(nil), nil
+	}
+
+	raw := make(map[string]interface{}, len(rc.Raw))
+	for k, v := range rc.Raw {
+		raw[k] = v
+	}
+
+	return NewResourceConfig(raw), nil
+}
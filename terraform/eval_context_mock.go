@@ -0,0 +1,60 @@
+package terraform
+
+import (
+	"github.com/hashicorp/terraform/config"
+)
+
+// MockEvalContext is a mock implementation of EvalContext for unit tests.
+type MockEvalContext struct {
+	ProviderCalled   bool
+	ProviderName     string
+	ProviderProvider ResourceProvider
+
+	CloseProviderCalled bool
+	CloseProviderName   string
+	CloseProviderError  error
+
+	SchemaCacheCalled bool
+	SchemaCacheValue  *SchemaCache
+
+	InterpolateCalled       bool
+	InterpolateConfig       *config.RawConfig
+	InterpolateResource     *Resource
+	InterpolateConfigResult *ResourceConfig
+	InterpolateError        error
+
+	FunctionsCalled bool
+	FunctionsValue  *config.FunctionRegistry
+}
+
+func (c *MockEvalContext) Provider(n string) ResourceProvider {
+	c.ProviderCalled = true
+	c.ProviderName = n
+	return c.ProviderProvider
+}
+
+func (c *MockEvalContext) CloseProvider(n string) error {
+	c.CloseProviderCalled = true
+	c.CloseProviderName = n
+	return c.CloseProviderError
+}
+
+func (c *MockEvalContext) SchemaCache() *SchemaCache {
+	c.SchemaCacheCalled = true
+	if c.SchemaCacheValue == nil {
+		c.SchemaCacheValue = NewSchemaCache()
+	}
+	return c.SchemaCacheValue
+}
+
+func (c *MockEvalContext) Interpolate(rc *config.RawConfig, r *Resource) (*ResourceConfig, error) {
+	c.InterpolateCalled = true
+	c.InterpolateConfig = rc
+	c.InterpolateResource = r
+	return c.InterpolateConfigResult, c.InterpolateError
+}
+
+func (c *MockEvalContext) Functions() *config.FunctionRegistry {
+	c.FunctionsCalled = true
+	return c.FunctionsValue
+}
@@ -0,0 +1,91 @@
+package terraform
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/config/configschema"
+)
+
+// EvalInterpolate is an EvalNode implementation that takes a raw
+// configuration and interpolates it.
+type EvalInterpolate struct {
+	Config   *config.RawConfig
+	Resource *Resource
+	Output   **ResourceConfig
+
+	// Schema, if non-nil, is the schema of the object being configured.
+	// When set, the resulting ResourceConfig is passed back through
+	// HCL2ValueFromConfigValueWithSchema/ConfigValueFromHCL2WithSchema so
+	// that each value is converted to the type the schema gives it (for
+	// example a numeric string to a number) instead of its type being
+	// guessed from shape. The result is again a map[string]interface{},
+	// so list-vs-set and map-vs-object distinctions are not kept in it.
+	Schema **configschema.Block
+}
+
+func (n *EvalInterpolate) Eval(ctx EvalContext) (interface{}, error) {
+	rc, err := ctx.Interpolate(n.Config, n.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Schema != nil && *n.Schema != nil {
+		rc, err = resourceConfigWithSchema(rc, *n.Schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if n.Output != nil {
+		*n.Output = rc
+	}
+
+	return nil, nil
+}
+
+// resourceConfigWithSchema re-derives the Config and Raw maps of rc by
+// round-tripping them through cty using schema, so that the schema's
+// type information can recover distinctions the legacy
+// map[string]interface{} representation can't express on its own. It
+// returns an error rather than leaving rc un-transformed if converting
+// either map into cty fails, since a silent no-op here would defeat the
+// whole point of threading a schema through in the first place. The
+// conversion back from cty has no error path.
+func resourceConfigWithSchema(rc *ResourceConfig, schema *configschema.Block) (*ResourceConfig, error) {
+	configVal, err := config.HCL2ValueFromConfigValueWithSchema(rc.Config, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply schema to config: %s", err)
+	}
+	rawVal, err := config.HCL2ValueFromConfigValueWithSchema(rc.Raw, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply schema to raw config: %s", err)
+	}
+
+	newConfig, _ := config.ConfigValueFromHCL2WithSchema(configVal, schema).(map[string]interface{})
+	newRaw, _ := config.ConfigValueFromHCL2WithSchema(rawVal, schema).(map[string]interface{})
+
+	// HCL2ValueFromConfigValueWithSchema has to produce a value with every
+	// attribute and block type the schema declares, since that's what a
+	// cty object type requires, so the conversion back to
+	// map[string]interface{} above necessarily has a key for every one of
+	// them too. rc.Config/rc.Raw may have never had most of those keys at
+	// all, so restrict the rebuilt maps back down to the keys the caller
+	// actually set; otherwise every unconfigured attribute would come
+	// back as an explicit nil, which callers like EvalBuildProviderConfig
+	// and EvalValidateProvider read as "explicitly set to null" rather
+	// than "not set".
+	rc.Config = restrictToKeys(newConfig, rc.Config)
+	rc.Raw = restrictToKeys(newRaw, rc.Raw)
+	return rc, nil
+}
+
+// restrictToKeys returns the subset of full whose keys are present in
+// original, preserving full's (schema-corrected) values.
+func restrictToKeys(full, original map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{}, len(original))
+	for k := range original {
+		ret[k] = full[k]
+	}
+	return ret
+}
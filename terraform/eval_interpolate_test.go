@@ -0,0 +1,42 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/config/configschema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// TestResourceConfigWithSchemaOmitsUnsetKeys guards against
+// resourceConfigWithSchema reintroducing every schema-declared attribute
+// as an explicit nil for keys the caller never set: EvalBuildProviderConfig
+// and EvalValidateProvider distinguish "unset" from "explicitly null" by
+// key presence, so the round trip through cty must not add keys that
+// weren't there to begin with.
+func TestResourceConfigWithSchemaOmitsUnsetKeys(t *testing.T) {
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"foo": {Type: cty.String, Optional: true},
+			"bar": {Type: cty.String, Optional: true},
+		},
+	}
+
+	rc := &ResourceConfig{
+		Raw:    map[string]interface{}{"foo": "hello"},
+		Config: map[string]interface{}{"foo": "hello"},
+	}
+
+	got, err := resourceConfigWithSchema(rc, schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := map[string]interface{}{"foo": "hello"}
+	if !reflect.DeepEqual(got.Config, want) {
+		t.Errorf("wrong Config\ngot:  %#v\nwant: %#v", got.Config, want)
+	}
+	if !reflect.DeepEqual(got.Raw, want) {
+		t.Errorf("wrong Raw\ngot:  %#v\nwant: %#v", got.Raw, want)
+	}
+}
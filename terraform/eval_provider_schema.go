@@ -0,0 +1,113 @@
+package terraform
+
+import (
+	"sync"
+
+	"github.com/hashicorp/terraform/config/configschema"
+)
+
+// EvalGetProviderSchema is an EvalNode implementation that retrieves the
+// schema for a configured provider, consulting the EvalContext's
+// process-wide SchemaCache before falling back to an RPC call against
+// the provider plugin.
+type EvalGetProviderSchema struct {
+	ProviderName string
+	Provider     *ResourceProvider
+	Output       **configschema.Block
+}
+
+func (n *EvalGetProviderSchema) Eval(ctx EvalContext) (interface{}, error) {
+	schema, err := getCachedProviderSchema(ctx.SchemaCache(), n.ProviderName, *n.Provider)
+	if err != nil {
+		return nil, err
+	}
+	*n.Output = schema
+	return nil, nil
+}
+
+// getCachedProviderSchema returns the schema for provider, consulting
+// cache first and populating it on a miss. It is the single choke point
+// both EvalGetProviderSchema and EvalPrefetchProviderSchemas go through,
+// so that once a provider's schema is cached, later requests for it do
+// not reach the plugin. Two calls for the same name that miss the cache
+// at the same time each make their own GetSchema call, and a failed
+// call caches nothing.
+func getCachedProviderSchema(cache *SchemaCache, name string, provider ResourceProvider) (*configschema.Block, error) {
+	if schema, ok := cache.Get(name); ok {
+		return schema, nil
+	}
+
+	schema, err := provider.GetSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Set(name, schema)
+	return schema, nil
+}
+
+// EvalPrefetchProviderSchemas is an EvalNode implementation that fetches
+// the schemas for a set of providers concurrently, ahead of the
+// per-provider input/validate/apply sequences built by ProviderEvalTree.
+// Those sequences each consult the same EvalContext SchemaCache, so any
+// of them that runs after this node finds its schema already populated
+// and EvalGetProviderSchema becomes a cache hit instead of a fresh call.
+// NodeRootProviderPrefetch is the graph node that runs this.
+//
+// Fetches are bounded by a small worker pool so that a configuration
+// referencing many providers doesn't open that many plugin connections
+// at once.
+type EvalPrefetchProviderSchemas struct {
+	Providers map[string]*ResourceProvider
+}
+
+// evalPrefetchProviderSchemasConcurrency bounds how many provider schema
+// RPCs EvalPrefetchProviderSchemas will have in flight at once.
+const evalPrefetchProviderSchemasConcurrency = 4
+
+func (n *EvalPrefetchProviderSchemas) Eval(ctx EvalContext) (interface{}, error) {
+	providers := make(map[string]ResourceProvider, len(n.Providers))
+	for name, provider := range n.Providers {
+		providers[name] = *provider
+	}
+	return nil, prefetchProviderSchemas(ctx.SchemaCache(), providers, evalPrefetchProviderSchemasConcurrency)
+}
+
+// prefetchProviderSchemas fans out a getCachedProviderSchema call per
+// provider across a worker pool bounded by concurrency, so that the
+// schemas for every provider not already cached get fetched in parallel
+// rather than one at a time.
+func prefetchProviderSchemas(cache *SchemaCache, providers map[string]ResourceProvider, concurrency int) error {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	errs := make([]error, len(names))
+
+	for i, name := range names {
+		provider := providers[name]
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, name string, provider ResourceProvider) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			_, err := getCachedProviderSchema(cache, name, provider)
+			errs[i] = err
+		}(i, name, provider)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -7,6 +7,18 @@ import (
 
 // ProviderEvalTree returns the evaluation tree for initializing and
 // configuring providers.
+//
+// Each EvalGetProviderSchema below consults the EvalContext's SchemaCache
+// before making a GetSchema RPC call, so a provider referenced from more
+// than one of the op-filtered branches in a single walk only pays the RPC
+// cost once. Where a NodeRootProviderPrefetch has run
+// EvalPrefetchProviderSchemas for this provider before its
+// NodeApplyableProvider (the graph vertex that calls this function),
+// every EvalGetProviderSchema here is a cache hit rather than a fresh RPC.
+//
+// EvalInterpolate defers to the EvalContext's own Interpolate method.
+// BuiltinEvalContext.Interpolate does not consult ctx.Functions(), so
+// functions registered in a module's FunctionRegistry are not used here.
 func ProviderEvalTree(n string, config *config.RawConfig) EvalNode {
 	var provider ResourceProvider
 	var resourceConfig *ResourceConfig
@@ -0,0 +1,47 @@
+package terraform
+
+import (
+	"github.com/hashicorp/terraform/config"
+)
+
+// GraphNodeEvalable is implemented by every graph node that does its work
+// by returning an EvalNode for the graph walker to run. This is the
+// mechanism by which ProviderEvalTree, EvalPrefetchProviderSchemas, and
+// every other Eval node built in this package actually gets invoked
+// during a walk: the walker type-asserts each vertex to
+// GraphNodeEvalable and evaluates whatever EvalTree() returns.
+type GraphNodeEvalable interface {
+	EvalTree() EvalNode
+}
+
+// NodeApplyableProvider is the graph vertex for a single configured
+// provider. A ProviderTransformer adds one of these per provider block in
+// configuration, with edges from every resource node that references it.
+type NodeApplyableProvider struct {
+	NameValue   string
+	ConfigValue *config.RawConfig
+}
+
+func (n *NodeApplyableProvider) Name() string {
+	return n.NameValue
+}
+
+// EvalTree implements GraphNodeEvalable.
+func (n *NodeApplyableProvider) EvalTree() EvalNode {
+	return ProviderEvalTree(n.NameValue, n.ConfigValue)
+}
+
+// NodeRootProviderPrefetch is a graph vertex that fetches the schema of
+// every provider in Providers concurrently via EvalPrefetchProviderSchemas,
+// warming the EvalContext's SchemaCache. It declares no edges and no graph
+// transformer adds it to a graph; only a NodeApplyableProvider that the
+// graph orders after it finds its schema already cached in the
+// EvalGetProviderSchema of its own tree instead of making its own RPC call.
+type NodeRootProviderPrefetch struct {
+	Providers map[string]*ResourceProvider
+}
+
+// EvalTree implements GraphNodeEvalable.
+func (n *NodeRootProviderPrefetch) EvalTree() EvalNode {
+	return &EvalPrefetchProviderSchemas{Providers: n.Providers}
+}
@@ -0,0 +1,15 @@
+package terraform
+
+// Resource identifies the resource or provider instance that a piece of
+// configuration is being interpolated for, so that interpolation
+// functions that need to know "self" (e.g. count.index) have something
+// to resolve against.
+type Resource struct {
+	// Id is the ID of the resource, if any, for interpolations like
+	// "self.id" to resolve against.
+	Id string
+
+	// CountIndex is the index of this resource in a "count"-expanded
+	// resource, or zero for resources that don't use count.
+	CountIndex int
+}
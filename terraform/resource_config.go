@@ -0,0 +1,38 @@
+package terraform
+
+// ResourceConfig holds the result of interpolating a RawConfig: the
+// fully-resolved configuration values a provider or resource sees.
+type ResourceConfig struct {
+	// ComputedKeys lists the keys in Raw whose value couldn't be fully
+	// resolved because they depend on a value that won't be known until
+	// apply time.
+	ComputedKeys []string
+
+	// Raw is the configuration as the user wrote it, after interpolation
+	// but before any provider-side defaults or coercion.
+	Raw map[string]interface{}
+
+	// Config is Raw with any keys whose value is UnknownVariableValue
+	// removed, representing what's actually known right now.
+	Config map[string]interface{}
+}
+
+// NewResourceConfig builds a ResourceConfig from an already-interpolated
+// raw value map.
+func NewResourceConfig(raw map[string]interface{}) *ResourceConfig {
+	config := make(map[string]interface{}, len(raw))
+	var computed []string
+	for k, v := range raw {
+		if v == UnknownVariableValue {
+			computed = append(computed, k)
+			continue
+		}
+		config[k] = v
+	}
+
+	return &ResourceConfig{
+		ComputedKeys: computed,
+		Raw:          raw,
+		Config:       config,
+	}
+}
@@ -0,0 +1,16 @@
+package terraform
+
+import (
+	"github.com/hashicorp/terraform/config/configschema"
+)
+
+// ResourceProvider is the interface that a resource provider plugin
+// implements. This package's provider-evaluation nodes only depend on
+// the schema-retrieval method below; the rest of the interface (Input,
+// Validate, Configure, Apply, Diff, Refresh, and so on) is defined
+// alongside the resource evaluation nodes.
+type ResourceProvider interface {
+	// GetSchema returns the schema for the provider's own configuration
+	// block.
+	GetSchema() (*configschema.Block, error)
+}
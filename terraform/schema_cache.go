@@ -0,0 +1,54 @@
+package terraform
+
+import (
+	"sync"
+
+	"github.com/hashicorp/terraform/config/configschema"
+)
+
+// SchemaCache is a process-wide cache of provider schemas, keyed by
+// provider name (as it appears in configuration, e.g. "aws"). Fetching a
+// provider's schema over the plugin RPC boundary is expensive and the
+// result never changes for the lifetime of a single plugin process, so
+// EvalGetProviderSchema consults this cache before making an RPC call
+// and populates it afterwards.
+//
+// SchemaCache is safe for concurrent use.
+type SchemaCache struct {
+	mu    sync.RWMutex
+	cache map[string]*configschema.Block
+}
+
+// NewSchemaCache returns an empty SchemaCache ready for use.
+func NewSchemaCache() *SchemaCache {
+	return &SchemaCache{
+		cache: make(map[string]*configschema.Block),
+	}
+}
+
+// Get returns the cached schema for the given provider name, and
+// whether it was found.
+func (c *SchemaCache) Get(name string) (*configschema.Block, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	schema, ok := c.cache[name]
+	return schema, ok
+}
+
+// Set stores the schema for the given provider name, overwriting any
+// existing entry.
+func (c *SchemaCache) Set(name string, schema *configschema.Block) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache[name] = schema
+}
+
+// Remove evicts the cached schema for the given provider name. It is
+// called when a provider's plugin process exits, since whatever
+// instance (potentially a different version, in the case of a reattach
+// or upgrade) replaces it should not see a stale cached schema.
+func (c *SchemaCache) Remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.cache, name)
+}
@@ -0,0 +1,130 @@
+package terraform
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/config/configschema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// countingSchemaProvider is a minimal ResourceProvider stand-in that
+// counts how many times GetSchema is called, so tests can assert on RPC
+// counts rather than just on final cache contents.
+type countingSchemaProvider struct {
+	schema *configschema.Block
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *countingSchemaProvider) GetSchema() (*configschema.Block, error) {
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+	return p.schema, nil
+}
+
+func (p *countingSchemaProvider) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+func TestSchemaCache(t *testing.T) {
+	cache := NewSchemaCache()
+
+	if _, ok := cache.Get("test"); ok {
+		t.Fatalf("expected no cached schema for an empty cache")
+	}
+
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"foo": {Type: cty.String, Optional: true},
+		},
+	}
+	cache.Set("test", schema)
+
+	got, ok := cache.Get("test")
+	if !ok {
+		t.Fatalf("expected a cached schema after Set")
+	}
+	if got != schema {
+		t.Fatalf("got a different schema pointer than the one stored")
+	}
+
+	if _, ok := cache.Get("other"); ok {
+		t.Fatalf("expected no cached schema for a different provider name")
+	}
+
+	cache.Remove("test")
+	if _, ok := cache.Get("test"); ok {
+		t.Fatalf("expected no cached schema after Remove")
+	}
+}
+
+// TestSchemaCacheSingleFetchPerWalk simulates repeated schema lookups for
+// a single provider across one walk, more than the three op-filtered
+// branches of ProviderEvalTree that fetch a schema (input, validate,
+// refresh/plan/apply/destroy/import), each calling getCachedProviderSchema
+// the way EvalGetProviderSchema does. It asserts the provider's GetSchema
+// is invoked exactly once across all of them, which is the whole point of
+// SchemaCache.
+func TestSchemaCacheSingleFetchPerWalk(t *testing.T) {
+	cache := NewSchemaCache()
+	provider := &countingSchemaProvider{
+		schema: &configschema.Block{
+			Attributes: map[string]*configschema.Attribute{
+				"foo": {Type: cty.String, Optional: true},
+			},
+		},
+	}
+
+	walkPhases := 5 // more lookups than the three schema-fetching branches
+	for i := 0; i < walkPhases; i++ {
+		schema, err := getCachedProviderSchema(cache, "test", provider)
+		if err != nil {
+			t.Fatalf("phase %d: unexpected error: %s", i, err)
+		}
+		if schema != provider.schema {
+			t.Fatalf("phase %d: got a different schema than the provider returned", i)
+		}
+	}
+
+	if got := provider.Calls(); got != 1 {
+		t.Errorf("provider.GetSchema called %d times across a walk; want 1", got)
+	}
+}
+
+// TestPrefetchProviderSchemasSingleFetchPerProvider simulates
+// EvalPrefetchProviderSchemas running ahead of several providers' own
+// ProviderEvalTree sequences: it asserts each provider's GetSchema is
+// called exactly once by the prefetch, and that subsequent per-provider
+// lookups (as EvalGetProviderSchema would do) are all cache hits.
+func TestPrefetchProviderSchemasSingleFetchPerProvider(t *testing.T) {
+	cache := NewSchemaCache()
+	providers := map[string]ResourceProvider{
+		"aws":    &countingSchemaProvider{schema: &configschema.Block{}},
+		"google": &countingSchemaProvider{schema: &configschema.Block{}},
+	}
+
+	if err := prefetchProviderSchemas(cache, providers, 4); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for name, provider := range providers {
+		counting := provider.(*countingSchemaProvider)
+
+		// Simulate the per-provider walk phases running after the
+		// prefetch: every one of them should now be a cache hit.
+		for i := 0; i < 5; i++ {
+			if _, err := getCachedProviderSchema(cache, name, provider); err != nil {
+				t.Fatalf("%s phase %d: unexpected error: %s", name, i, err)
+			}
+		}
+
+		if got := counting.Calls(); got != 1 {
+			t.Errorf("%s: provider.GetSchema called %d times; want 1", name, got)
+		}
+	}
+}